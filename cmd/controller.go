@@ -0,0 +1,180 @@
+package main
+
+import "github.com/hajimehoshi/ebiten/v2"
+
+// GameState is a read-only snapshot of the game handed to a Controller each
+// frame. Controllers must not mutate the slices/points they are given.
+type GameState struct {
+	Snake []Point
+	Food  Point
+	Dir   Point
+}
+
+// Controller decides the direction the snake should be moving. It is
+// polled every frame so it can react to input (or recompute a path) before
+// the next tick boundary, where Game.dir is actually advanced.
+type Controller interface {
+	// NextDir returns the direction the snake should move on the next
+	// tick. Returning state.Dir keeps the snake going straight.
+	NextDir(state GameState) Point
+}
+
+// HumanController reads arrow keys / WASD from the keyboard, or the
+// D-pad/left stick of a connected gamepad if one is active.
+type HumanController struct{}
+
+// NewHumanController creates a keyboard/gamepad-driven controller.
+func NewHumanController() *HumanController {
+	return &HumanController{}
+}
+
+// NextDir implements Controller.
+func (h *HumanController) NextDir(state GameState) Point {
+	if dir, ok := gamepadDir(state.Dir); ok {
+		return dir
+	}
+
+	dir := state.Dir
+
+	if ebiten.IsKeyPressed(ebiten.KeyArrowUp) || ebiten.IsKeyPressed(ebiten.KeyW) {
+		if state.Dir.Y != 1 {
+			dir = Point{0, -1}
+		}
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyArrowDown) || ebiten.IsKeyPressed(ebiten.KeyS) {
+		if state.Dir.Y != -1 {
+			dir = Point{0, 1}
+		}
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyArrowLeft) || ebiten.IsKeyPressed(ebiten.KeyA) {
+		if state.Dir.X != 1 {
+			dir = Point{-1, 0}
+		}
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyArrowRight) || ebiten.IsKeyPressed(ebiten.KeyD) {
+		if state.Dir.X != -1 {
+			dir = Point{1, 0}
+		}
+	}
+
+	return dir
+}
+
+// AIController steers the snake automatically: it BFS-searches for the
+// shortest safe path from the head to the food, and when no such path
+// exists it falls back to chasing its own tail (a cheap approximation of
+// "longest path to tail") so it survives until an opening reappears.
+type AIController struct{}
+
+// NewAIController creates a pathfinding autopilot controller.
+func NewAIController() *AIController {
+	return &AIController{}
+}
+
+// NextDir implements Controller.
+func (a *AIController) NextDir(state GameState) Point {
+	if dir, ok := bfsDirTo(state, state.Food); ok {
+		return dir
+	}
+
+	if len(state.Snake) > 0 {
+		tail := state.Snake[len(state.Snake)-1]
+		if dir, ok := bfsDirTo(state, tail); ok {
+			return dir
+		}
+	}
+
+	if dir, ok := firstSafeDir(state); ok {
+		return dir
+	}
+
+	return state.Dir
+}
+
+// bfsDirTo runs a breadth-first search from the snake's head to target,
+// treating every snake segment as an obstacle, and returns the direction
+// of the first step of the shortest path found.
+func bfsDirTo(state GameState, target Point) (Point, bool) {
+	if len(state.Snake) == 0 {
+		return Point{}, false
+	}
+	head := state.Snake[0]
+
+	blocked := make(map[Point]bool, len(state.Snake))
+	for _, s := range state.Snake {
+		blocked[s] = true
+	}
+
+	visited := map[Point]Point{head: head}
+	queue := []Point{head}
+
+	dirs := []Point{{0, -1}, {0, 1}, {-1, 0}, {1, 0}}
+
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+
+		if cur == target {
+			// Walk back to the step adjacent to head.
+			step := cur
+			for visited[step] != head {
+				step = visited[step]
+			}
+			return Point{step.X - head.X, step.Y - head.Y}, true
+		}
+
+		for _, d := range dirs {
+			next := Point{cur.X + d.X, cur.Y + d.Y}
+			if !inBounds(next) {
+				continue
+			}
+			if blocked[next] && next != target {
+				continue
+			}
+			if _, seen := visited[next]; seen {
+				continue
+			}
+			visited[next] = cur
+			queue = append(queue, next)
+		}
+	}
+
+	return Point{}, false
+}
+
+// firstSafeDir returns the first adjacent direction that doesn't
+// immediately collide with a wall or the snake's own body.
+func firstSafeDir(state GameState) (Point, bool) {
+	if len(state.Snake) == 0 {
+		return Point{}, false
+	}
+	head := state.Snake[0]
+	blocked := make(map[Point]bool, len(state.Snake))
+	for _, s := range state.Snake {
+		blocked[s] = true
+	}
+
+	for _, d := range []Point{{0, -1}, {0, 1}, {-1, 0}, {1, 0}} {
+		next := Point{head.X + d.X, head.Y + d.Y}
+		if inBounds(next) && !blocked[next] {
+			return d, true
+		}
+	}
+	return Point{}, false
+}
+
+// controllerName returns a short label for the active controller, used on
+// the scoreboard and HUD.
+func (g *Game) controllerName() string {
+	switch g.controller.(type) {
+	case *AIController:
+		return "AI"
+	default:
+		return "Human"
+	}
+}
+
+// inBounds reports whether p lies within the playfield grid.
+func inBounds(p Point) bool {
+	return p.X >= 0 && p.X < gridW && p.Y >= 0 && p.Y < gridH
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// gamepadDeadzone is the minimum analog stick magnitude that counts as a
+// directional input, to avoid drift on worn sticks.
+const gamepadDeadzone = 0.2
+
+// activeGamepadState tracks which connected pad, if any, is currently
+// steering the snake.
+type activeGamepadState struct {
+	id  ebiten.GamepadID
+	set bool
+}
+
+var activeGamepad activeGamepadState
+
+// updateActiveGamepad polls for hot-plug events and, following the
+// pattern used in carotidartillery's activeGamepad handling, promotes the
+// first connected pad with any button pressed to be the active one. It
+// clears the active pad again on disconnect so input falls back to the
+// keyboard.
+func updateActiveGamepad() {
+	if activeGamepad.set && inpututil.IsGamepadJustDisconnected(activeGamepad.id) {
+		activeGamepad = activeGamepadState{}
+	}
+
+	if activeGamepad.set {
+		return
+	}
+
+	for _, id := range ebiten.AppendGamepadIDs(nil) {
+		if gamepadAnyButtonPressed(id) {
+			activeGamepad = activeGamepadState{id: id, set: true}
+			return
+		}
+	}
+}
+
+// gamepadAnyButtonPressed reports whether any button on id is currently
+// held down, preferring the standard layout when the driver exposes one.
+func gamepadAnyButtonPressed(id ebiten.GamepadID) bool {
+	if ebiten.IsStandardGamepadLayoutAvailable(id) {
+		for b := ebiten.StandardGamepadButton(0); b < ebiten.StandardGamepadButtonMax; b++ {
+			if ebiten.IsStandardGamepadButtonPressed(id, b) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for i := 0; i < ebiten.GamepadButtonNum(id); i++ {
+		if ebiten.IsGamepadButtonPressed(id, ebiten.GamepadButton(i)) {
+			return true
+		}
+	}
+	return false
+}
+
+// gamepadDir reads the D-pad and left analog stick of the active pad and
+// returns the direction it implies, if any, given the current heading
+// cur (used to reject 180-degree reversals the same way keyboard input
+// does).
+func gamepadDir(cur Point) (Point, bool) {
+	if !activeGamepad.set || !ebiten.IsStandardGamepadLayoutAvailable(activeGamepad.id) {
+		return Point{}, false
+	}
+	id := activeGamepad.id
+
+	switch {
+	case ebiten.IsStandardGamepadButtonPressed(id, ebiten.StandardGamepadButtonLeftTop) && cur.Y != 1:
+		return Point{0, -1}, true
+	case ebiten.IsStandardGamepadButtonPressed(id, ebiten.StandardGamepadButtonLeftBottom) && cur.Y != -1:
+		return Point{0, 1}, true
+	case ebiten.IsStandardGamepadButtonPressed(id, ebiten.StandardGamepadButtonLeftLeft) && cur.X != 1:
+		return Point{-1, 0}, true
+	case ebiten.IsStandardGamepadButtonPressed(id, ebiten.StandardGamepadButtonLeftRight) && cur.X != -1:
+		return Point{1, 0}, true
+	}
+
+	x := ebiten.StandardGamepadAxisValue(id, ebiten.StandardGamepadAxisLeftStickHorizontal)
+	y := ebiten.StandardGamepadAxisValue(id, ebiten.StandardGamepadAxisLeftStickVertical)
+	switch {
+	case y < -gamepadDeadzone && cur.Y != 1:
+		return Point{0, -1}, true
+	case y > gamepadDeadzone && cur.Y != -1:
+		return Point{0, 1}, true
+	case x < -gamepadDeadzone && cur.X != 1:
+		return Point{-1, 0}, true
+	case x > gamepadDeadzone && cur.X != -1:
+		return Point{1, 0}, true
+	}
+
+	return Point{}, false
+}
+
+// gamepadButtonJustPressed reports whether button was just pressed this
+// frame on the active pad.
+func gamepadButtonJustPressed(button ebiten.StandardGamepadButton) bool {
+	return activeGamepad.set && ebiten.IsStandardGamepadLayoutAvailable(activeGamepad.id) &&
+		inpututil.IsStandardGamepadButtonJustPressed(activeGamepad.id, button)
+}
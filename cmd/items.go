@@ -0,0 +1,162 @@
+package main
+
+// ItemType identifies a power-up kind, inspired by the garlic/holy-water
+// items in the carotidartillery references.
+type ItemType int
+
+const (
+	ItemSlowMo ItemType = iota
+	ItemGhost
+	ItemShrink
+	ItemDoubleScore
+)
+
+// String implements fmt.Stringer.
+func (t ItemType) String() string {
+	switch t {
+	case ItemSlowMo:
+		return "Slow-Mo"
+	case ItemGhost:
+		return "Ghost"
+	case ItemShrink:
+		return "Shrink"
+	case ItemDoubleScore:
+		return "2x Score"
+	default:
+		return "?"
+	}
+}
+
+// itemTypes lists every spawnable item kind.
+var itemTypes = []ItemType{ItemSlowMo, ItemGhost, ItemShrink, ItemDoubleScore}
+
+const (
+	itemSpawnEveryTicks = 40 // ticks between spawn attempts
+	itemMaxOnBoard      = 2  // concurrent uncollected items allowed
+	itemTTLTicks        = 60 // ticks an uncollected item lives before despawning
+
+	slowMoDurationTicks   = 30
+	slowMoTickSpeedDelta  = 4 // added to tickSpeed while Slow-Mo is active
+	ghostDurationTicks    = 20
+	doubleScoreFoodsGiven = 2
+	shrinkSegmentsRemoved = 3
+)
+
+// Item is a power-up sitting on the grid waiting to be picked up.
+type Item struct {
+	Type ItemType
+	Pos  Point
+	TTL  int
+}
+
+// randomFreeTile finds a tile occupied by neither the snake, the food
+// nor an existing item, giving up after a bounded number of attempts. It
+// draws from the game's seeded RNG so runs stay reproducible for replay.
+func (g *Game) randomFreeTile() (Point, bool) {
+	const attempts = 50
+	for i := 0; i < attempts; i++ {
+		p := Point{g.rng.Intn(gridW), g.rng.Intn(gridH)}
+		if !g.tileOccupied(p) {
+			return p, true
+		}
+	}
+	return Point{}, false
+}
+
+// tileOccupied reports whether p is covered by the snake, the food, or an
+// item already on the board.
+func (g *Game) tileOccupied(p Point) bool {
+	if p == g.food {
+		return true
+	}
+	for _, s := range g.snake {
+		if s == p {
+			return true
+		}
+	}
+	for _, it := range g.items {
+		if it.Pos == p {
+			return true
+		}
+	}
+	return false
+}
+
+// spawnItem places a new random item on a free tile, provided the board
+// isn't already at itemMaxOnBoard.
+func (g *Game) spawnItem() {
+	if len(g.items) >= itemMaxOnBoard {
+		return
+	}
+	pos, ok := g.randomFreeTile()
+	if !ok {
+		return
+	}
+	g.items = append(g.items, Item{
+		Type: itemTypes[g.rng.Intn(len(itemTypes))],
+		Pos:  pos,
+		TTL:  itemTTLTicks,
+	})
+}
+
+// applyItemEffect resolves picking up an item of type t: duration-based
+// effects (SlowMo, Ghost) start or refresh their countdown in
+// g.activeEffects; Shrink and DoubleScore act immediately.
+func (g *Game) applyItemEffect(t ItemType) {
+	switch t {
+	case ItemSlowMo:
+		if g.activeEffects[ItemSlowMo] == 0 {
+			g.tickSpeed += slowMoTickSpeedDelta
+		}
+		g.activeEffects[ItemSlowMo] = slowMoDurationTicks
+	case ItemGhost:
+		g.activeEffects[ItemGhost] = ghostDurationTicks
+	case ItemShrink:
+		for i := 0; i < shrinkSegmentsRemoved && len(g.snake) > 1; i++ {
+			g.snake = g.snake[:len(g.snake)-1]
+		}
+	case ItemDoubleScore:
+		g.doubleFoodsLeft += doubleScoreFoodsGiven
+	}
+}
+
+// tickEffects counts down every active effect by one tick, undoing
+// SlowMo's speed change once it expires.
+func (g *Game) tickEffects() {
+	for t, ticks := range g.activeEffects {
+		if ticks <= 0 {
+			continue
+		}
+		ticks--
+		if ticks > 0 {
+			g.activeEffects[t] = ticks
+			continue
+		}
+		if t == ItemSlowMo {
+			g.tickSpeed -= slowMoTickSpeedDelta
+			if g.tickSpeed < 2 {
+				g.tickSpeed = 2
+			}
+		}
+		delete(g.activeEffects, t)
+	}
+}
+
+// tickItemSpawner advances the spawn timer (spawning a new item once it
+// elapses) and despawns any item whose TTL has run out.
+func (g *Game) tickItemSpawner() {
+	g.itemSpawnTimer--
+	if g.itemSpawnTimer <= 0 {
+		g.spawnItem()
+		g.itemSpawnTimer = itemSpawnEveryTicks
+	}
+
+	live := g.items[:0]
+	for _, it := range g.items {
+		it.TTL--
+		if it.TTL > 0 {
+			live = append(live, it)
+		}
+	}
+	g.items = live
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"image/color"
 	"log"
@@ -8,7 +9,6 @@ import (
 	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 )
 
 // === Configuration constants ===
@@ -19,9 +19,8 @@ const (
 	screenW  = gridW * tileSize
 	screenH  = gridH * tileSize
 
-	baseTickSpeed = 8 // frames per movement (lower = faster)
-	speedUpEvery  = 5 // increase speed every N food eaten
-	speedDelta    = 1 // how much to reduce tickSpeed
+	speedUpEvery = 5 // increase speed every N food eaten
+	speedDelta   = 1 // how much to reduce tickSpeed
 )
 
 // Point represents a position on the grid
@@ -39,34 +38,79 @@ type Game struct {
 	tickCount int
 	tickSpeed int
 	gameOver  bool
-	started   bool
+
+	difficulty    Difficulty
+	baseTickSpeed int
+	startedAt     time.Time
+
+	rng             *rand.Rand
+	seed            int64
+	simTick         int
+	isReplay        bool
+	recording       bool
+	lastRecordedDir Point
+	replayEvents    []ReplayEvent
+
+	items           []Item
+	activeEffects   map[ItemType]int // ticks remaining, keyed by effect
+	doubleFoodsLeft int
+	itemSpawnTimer  int
+
+	frame int // advances every Update call, used for sprite animation
+
+	controller Controller
+	scenes     *SceneManager
+	scoreboard *Scoreboard
+
+	tiles *Tilesheet // nil if the embedded sheet failed to decode
 
 	tileSnake *ebiten.Image
 	tileHead  *ebiten.Image
 	tileFood  *ebiten.Image
+	itemTiles map[ItemType]*ebiten.Image
 }
 
 // NewGame initializes the game instance and assets
 func NewGame() *Game {
 	g := &Game{
-		tickSpeed: baseTickSpeed,
+		controller: NewHumanController(),
+		difficulty: DifficultyNormal,
+		scoreboard: LoadScoreboard(),
 	}
 
-	// Create simple colored tiles (snake, food, head)
-	g.tileSnake = ebiten.NewImage(tileSize, tileSize)
-	g.tileSnake.Fill(color.RGBA{R: 50, G: 200, B: 50, A: 255})
-	g.tileHead = ebiten.NewImage(tileSize, tileSize)
-	g.tileHead.Fill(color.RGBA{R: 0, G: 120, B: 255, A: 255})
-	g.tileFood = ebiten.NewImage(tileSize, tileSize)
-	g.tileFood.Fill(color.RGBA{R: 220, G: 40, B: 40, A: 255})
+	// Flat-color tiles used if the embedded tilesheet fails to load.
+	g.tileSnake = fallbackTile(color.RGBA{R: 50, G: 200, B: 50, A: 255})
+	g.tileHead = fallbackTile(color.RGBA{R: 0, G: 120, B: 255, A: 255})
+	g.tileFood = fallbackTile(color.RGBA{R: 220, G: 40, B: 40, A: 255})
 
-	rand.Seed(time.Now().UnixNano())
-	g.reset()
+	g.tiles = tilesheetOrFallback()
+
+	g.itemTiles = map[ItemType]*ebiten.Image{
+		ItemSlowMo:      fallbackTile(color.RGBA{R: 120, G: 120, B: 255, A: 255}),
+		ItemGhost:       fallbackTile(color.RGBA{R: 230, G: 230, B: 230, A: 180}),
+		ItemShrink:      fallbackTile(color.RGBA{R: 255, G: 180, B: 40, A: 255}),
+		ItemDoubleScore: fallbackTile(color.RGBA{R: 255, G: 215, B: 0, A: 255}),
+	}
+
+	g.scenes = NewSceneManager(g)
 	return g
 }
 
-// reset restarts the game to the initial state
+// SetController swaps the controller driving the snake, e.g. to switch
+// between human input and the AI autopilot.
+func (g *Game) SetController(c Controller) {
+	g.controller = c
+}
+
+// reset restarts the simulation to its initial state using the currently
+// selected difficulty. Called when a run starts from the menu, or once
+// up front (with a fixed seed) when launching into replay playback.
 func (g *Game) reset() {
+	if !g.isReplay {
+		g.seed = time.Now().UnixNano()
+	}
+	g.rng = rand.New(rand.NewSource(g.seed))
+
 	cx, cy := gridW/2, gridH/2
 	g.snake = []Point{{cx, cy}, {cx - 1, cy}, {cx - 2, cy}}
 	g.dir = Point{1, 0}
@@ -74,16 +118,28 @@ func (g *Game) reset() {
 	g.placeFood()
 	g.score = 0
 	g.tickCount = 0
-	g.tickSpeed = baseTickSpeed
+	g.baseTickSpeed = g.difficulty.TickSpeed()
+	g.tickSpeed = g.baseTickSpeed
 	g.gameOver = false
-	g.started = true
+	g.startedAt = time.Now()
+
+	g.simTick = 0
+	g.recording = !g.isReplay
+	g.lastRecordedDir = g.dir
+	g.replayEvents = nil
+
+	g.items = nil
+	g.activeEffects = map[ItemType]int{}
+	g.doubleFoodsLeft = 0
+	g.itemSpawnTimer = itemSpawnEveryTicks
 }
 
-// placeFood randomly positions the food on a free tile
+// placeFood randomly positions the food on a free tile, using the game's
+// seeded RNG so runs (and their replays) are reproducible.
 func (g *Game) placeFood() {
 	for {
-		x := rand.Intn(gridW)
-		y := rand.Intn(gridH)
+		x := g.rng.Intn(gridW)
+		y := g.rng.Intn(gridH)
 		p := Point{x, y}
 		coll := false
 		for _, s := range g.snake {
@@ -99,48 +155,10 @@ func (g *Game) placeFood() {
 	}
 }
 
-// Update handles game logic and user input
-func (g *Game) Update() error {
-	// Restart if R is pressed after game over
-	if ebiten.IsKeyPressed(ebiten.KeyR) && g.gameOver {
-		g.reset()
-		return nil
-	}
-
-	// Input controls (arrow keys or WASD)
-	if ebiten.IsKeyPressed(ebiten.KeyArrowUp) || ebiten.IsKeyPressed(ebiten.KeyW) {
-		if g.dir.Y != 1 {
-			g.nextDir = Point{0, -1}
-		}
-	}
-	if ebiten.IsKeyPressed(ebiten.KeyArrowDown) || ebiten.IsKeyPressed(ebiten.KeyS) {
-		if g.dir.Y != -1 {
-			g.nextDir = Point{0, 1}
-		}
-	}
-	if ebiten.IsKeyPressed(ebiten.KeyArrowLeft) || ebiten.IsKeyPressed(ebiten.KeyA) {
-		if g.dir.X != 1 {
-			g.nextDir = Point{-1, 0}
-		}
-	}
-	if ebiten.IsKeyPressed(ebiten.KeyArrowRight) || ebiten.IsKeyPressed(ebiten.KeyD) {
-		if g.dir.X != -1 {
-			g.nextDir = Point{1, 0}
-		}
-	}
-
-	if g.gameOver {
-		return nil
-	}
-
-	// Control the update rate (snake speed)
-	g.tickCount++
-	if g.tickCount < g.tickSpeed {
-		return nil
-	}
-	g.tickCount = 0
-
-	// Update direction
+// tick advances the simulation by a single movement step. The caller is
+// responsible for gating calls to every tickSpeed frames.
+func (g *Game) tick() {
+	g.simTick++
 	g.dir = g.nextDir
 
 	// Calculate new head position
@@ -150,22 +168,38 @@ func (g *Game) Update() error {
 	// Check wall collision
 	if newHead.X < 0 || newHead.X >= gridW || newHead.Y < 0 || newHead.Y >= gridH {
 		g.gameOver = true
-		return nil
+		return
 	}
-	// Check self collision
-	for _, s := range g.snake {
-		if s == newHead {
-			g.gameOver = true
-			return nil
+	// Check self collision, unless Ghost lets the head pass through
+	if g.activeEffects[ItemGhost] == 0 {
+		for _, s := range g.snake {
+			if s == newHead {
+				g.gameOver = true
+				return
+			}
 		}
 	}
 
 	// Add new head at the front
 	g.snake = append([]Point{newHead}, g.snake...)
 
+	// Pick up any item sitting on the new head
+	for i, it := range g.items {
+		if it.Pos == newHead {
+			g.applyItemEffect(it.Type)
+			g.items = append(g.items[:i], g.items[i+1:]...)
+			break
+		}
+	}
+
 	// Check if food eaten
 	if newHead == g.food {
-		g.score++
+		if g.doubleFoodsLeft > 0 {
+			g.score += 2
+			g.doubleFoodsLeft--
+		} else {
+			g.score++
+		}
 		// Speed up every few points
 		if g.score%speedUpEvery == 0 && g.tickSpeed > 2 {
 			g.tickSpeed -= speedDelta
@@ -176,51 +210,97 @@ func (g *Game) Update() error {
 		g.snake = g.snake[:len(g.snake)-1]
 	}
 
-	return nil
+	g.tickEffects()
+	g.tickItemSpawner()
 }
 
-// Draw renders all game elements on screen
-func (g *Game) Draw(screen *ebiten.Image) {
-	screen.Fill(color.RGBA{R: 10, G: 10, B: 10, A: 255})
-
-	// Draw food
-	op := &ebiten.DrawImageOptions{}
-	op.GeoM.Translate(float64(g.food.X*tileSize), float64(g.food.Y*tileSize))
-	screen.DrawImage(g.tileFood, op)
-
-	// Draw snake
-	for i, p := range g.snake {
-		op := &ebiten.DrawImageOptions{}
-		op.GeoM.Translate(float64(p.X*tileSize), float64(p.Y*tileSize))
-		if i == 0 {
-			screen.DrawImage(g.tileHead, op)
-		} else {
-			screen.DrawImage(g.tileSnake, op)
-		}
-	}
+// pollDirection asks the controller for the direction to take on the
+// next tick and, while recording a run, appends a replay event whenever
+// it actually changes heading.
+func (g *Game) pollDirection() {
+	dir := g.controller.NextDir(GameState{
+		Snake: g.snake,
+		Food:  g.food,
+		Dir:   g.dir,
+	})
+	g.nextDir = dir
 
-	// HUD info
-	ebitenutil.DebugPrintAt(screen, fmt.Sprintf("Score: %d", g.score), 4, 4)
-	ebitenutil.DebugPrintAt(screen, fmt.Sprintf("Speed (frames/move): %d", g.tickSpeed), 4, 20)
-	ebitenutil.DebugPrintAt(screen, "Controls: Arrow keys or WASD. R = restart (on game over)", 4, 36)
+	if g.recording && dir != g.lastRecordedDir {
+		g.replayEvents = append(g.replayEvents, ReplayEvent{Tick: g.simTick, Dir: dir})
+		g.lastRecordedDir = dir
+	}
+}
 
-	// Game over message
-	if g.gameOver {
-		ebitenutil.DebugPrintAt(screen, "GAME OVER! Press R to restart.", screenW/2-120, screenH/2)
+// toReplay snapshots the just-finished run as a Replay, ready to be
+// gob-encoded to disk.
+func (g *Game) toReplay() Replay {
+	return Replay{
+		Seed:          g.seed,
+		Difficulty:    g.difficulty,
+		BaseTickSpeed: g.baseTickSpeed,
+		Events:        g.replayEvents,
 	}
 }
 
-// Layout defines the windowâ€™s internal resolution
+// Update delegates to whichever scene is currently active.
+func (g *Game) Update() error {
+	g.frame++
+	updateActiveGamepad()
+	return g.scenes.Update()
+}
+
+// Draw delegates to whichever scene is currently active.
+func (g *Game) Draw(screen *ebiten.Image) {
+	g.scenes.Draw(screen)
+}
+
+// Layout defines the window's internal resolution
 func (g *Game) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int) {
-	return screenW, screenH
+	return g.scenes.Layout(outsideWidth, outsideHeight)
 }
 
 func main() {
+	mode := flag.String("mode", "human", "controller mode: human or ai")
+	resetScores := flag.Bool("reset-scores", false, "clear the persisted high-score table and exit")
+	replayPath := flag.String("replay", "", "path to a .snkrep file to play back instead of starting a new run")
+	flag.Parse()
+
+	if *resetScores {
+		if err := LoadScoreboard().Reset(); err != nil {
+			log.Fatalf("reset scores: %v", err)
+		}
+		fmt.Println("snake: high-score table cleared")
+		return
+	}
+
 	ebiten.SetWindowSize(screenW, screenH)
 	ebiten.SetWindowTitle("Snake - Go + Ebiten")
 
 	game := NewGame()
 
+	if *replayPath != "" {
+		replay, err := LoadReplay(*replayPath)
+		if err != nil {
+			log.Fatalf("load replay: %v", err)
+		}
+		game.isReplay = true
+		game.seed = replay.Seed
+		game.difficulty = replay.Difficulty
+		game.reset()
+		game.tickSpeed = replay.BaseTickSpeed
+		game.SetController(NewReplayController(game, replay.Events))
+		game.scenes.Reset(NewReplayScene(game.scenes))
+	} else {
+		switch *mode {
+		case "ai":
+			game.SetController(NewAIController())
+		case "human":
+			// default controller set by NewGame
+		default:
+			log.Fatalf("unknown -mode %q: want \"human\" or \"ai\"", *mode)
+		}
+	}
+
 	if err := ebiten.RunGame(game); err != nil {
 		log.Fatal(err)
 	}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"encoding/gob"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ReplayEvent is one direction change during a recorded run, tagged with
+// the simulation tick (see Game.simTick) it takes effect on.
+type ReplayEvent struct {
+	Tick int
+	Dir  Point
+}
+
+// Replay is a deterministic record of a run: the RNG seed and starting
+// speed it was played with, plus every direction change. Replaying these
+// events against a Game seeded the same way reproduces the run exactly,
+// since food placement and item spawns are both drawn from that seed.
+type Replay struct {
+	Seed          int64
+	Difficulty    Difficulty
+	BaseTickSpeed int
+	Events        []ReplayEvent
+}
+
+// replayDir returns (and does not create) the directory replays are
+// stored under.
+func replayDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".local", "share", "snake", "replays"), nil
+}
+
+// SaveReplay gob-encodes r to a timestamped file under the replay
+// directory and returns the path written.
+func SaveReplay(r Replay, when time.Time) (string, error) {
+	dir, err := replayDir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, when.Format("20060102-150405.000")+".snkrep")
+
+	f, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	if err := gob.NewEncoder(f).Encode(r); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// LoadReplay decodes a gob-encoded replay file written by SaveReplay.
+func LoadReplay(path string) (Replay, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Replay{}, err
+	}
+	defer f.Close()
+
+	var r Replay
+	if err := gob.NewDecoder(f).Decode(&r); err != nil {
+		return Replay{}, fmt.Errorf("decode replay %s: %w", path, err)
+	}
+	return r, nil
+}
+
+// ReplayController drives the snake by replaying a recorded Replay
+// tick-for-tick instead of reading live input.
+type ReplayController struct {
+	game   *Game
+	events []ReplayEvent
+	idx    int
+	dir    Point
+}
+
+// NewReplayController creates a controller that replays events against
+// g. g must already be seeded identically to the original run (see
+// main's -replay handling, which sets Game.seed from the Replay before
+// calling Game.reset).
+func NewReplayController(g *Game, events []ReplayEvent) *ReplayController {
+	return &ReplayController{game: g, events: events}
+}
+
+// NextDir implements Controller.
+func (r *ReplayController) NextDir(state GameState) Point {
+	for r.idx < len(r.events) && r.events[r.idx].Tick <= r.game.simTick {
+		r.dir = r.events[r.idx].Dir
+		r.idx++
+	}
+	if r.idx == 0 {
+		return state.Dir
+	}
+	return r.dir
+}
@@ -0,0 +1,546 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"log"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// Difficulty controls how fast the snake moves.
+type Difficulty int
+
+const (
+	DifficultyEasy Difficulty = iota
+	DifficultyNormal
+	DifficultyHard
+)
+
+// TickSpeed returns the number of frames per movement tick for d (lower
+// is faster).
+func (d Difficulty) TickSpeed() int {
+	switch d {
+	case DifficultyEasy:
+		return 12
+	case DifficultyHard:
+		return 5
+	default:
+		return 8
+	}
+}
+
+// String implements fmt.Stringer.
+func (d Difficulty) String() string {
+	switch d {
+	case DifficultyEasy:
+		return "Easy"
+	case DifficultyHard:
+		return "Hard"
+	default:
+		return "Normal"
+	}
+}
+
+// Scene is one screen of the game: the menu, active play, the pause
+// overlay or the game-over screen.
+type Scene interface {
+	Update() error
+	Draw(screen *ebiten.Image)
+	Layout(outsideWidth, outsideHeight int) (int, int)
+}
+
+// SceneManager owns the stack of active scenes for a Game and always
+// updates/draws the one on top. Pushing a scene (e.g. pausing) leaves the
+// scene beneath it alive but idle; SwitchTo replaces the top outright.
+type SceneManager struct {
+	game  *Game
+	stack []Scene
+}
+
+// NewSceneManager creates a manager that starts on the menu scene.
+func NewSceneManager(g *Game) *SceneManager {
+	sm := &SceneManager{game: g}
+	sm.stack = []Scene{NewMenuScene(sm)}
+	return sm
+}
+
+// Push adds s on top of the stack, leaving the current scene paused
+// beneath it.
+func (sm *SceneManager) Push(s Scene) {
+	sm.stack = append(sm.stack, s)
+}
+
+// Pop removes the top scene, resuming whatever is beneath it. It is a
+// no-op if only one scene remains.
+func (sm *SceneManager) Pop() {
+	if len(sm.stack) > 1 {
+		sm.stack = sm.stack[:len(sm.stack)-1]
+	}
+}
+
+// SwitchTo replaces the top scene with s.
+func (sm *SceneManager) SwitchTo(s Scene) {
+	sm.stack[len(sm.stack)-1] = s
+}
+
+// Reset discards the whole stack and starts fresh on s, e.g. to launch
+// straight into replay playback instead of the menu.
+func (sm *SceneManager) Reset(s Scene) {
+	sm.stack = []Scene{s}
+}
+
+func (sm *SceneManager) top() Scene {
+	return sm.stack[len(sm.stack)-1]
+}
+
+// Update implements Scene by delegating to the top scene.
+func (sm *SceneManager) Update() error {
+	return sm.top().Update()
+}
+
+// Draw implements Scene by delegating to the top scene.
+func (sm *SceneManager) Draw(screen *ebiten.Image) {
+	sm.top().Draw(screen)
+}
+
+// Layout implements Scene by delegating to the top scene.
+func (sm *SceneManager) Layout(outsideWidth, outsideHeight int) (int, int) {
+	return sm.top().Layout(outsideWidth, outsideHeight)
+}
+
+// === MenuScene ===
+
+// MenuScene is the start screen: pick a difficulty, then start a run.
+type MenuScene struct {
+	sm         *SceneManager
+	difficulty Difficulty
+}
+
+// NewMenuScene creates the start screen defaulting to Normal difficulty.
+func NewMenuScene(sm *SceneManager) *MenuScene {
+	return &MenuScene{sm: sm, difficulty: DifficultyNormal}
+}
+
+// Update implements Scene.
+func (m *MenuScene) Update() error {
+	if inpututil.IsKeyJustPressed(ebiten.KeyArrowUp) || inpututil.IsKeyJustPressed(ebiten.KeyW) ||
+		gamepadButtonJustPressed(ebiten.StandardGamepadButtonLeftTop) {
+		if m.difficulty > DifficultyEasy {
+			m.difficulty--
+		}
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyArrowDown) || inpututil.IsKeyJustPressed(ebiten.KeyS) ||
+		gamepadButtonJustPressed(ebiten.StandardGamepadButtonLeftBottom) {
+		if m.difficulty < DifficultyHard {
+			m.difficulty++
+		}
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeySpace) ||
+		gamepadButtonJustPressed(ebiten.StandardGamepadButtonRightBottom) {
+		g := m.sm.game
+		g.difficulty = m.difficulty
+		g.reset()
+		m.sm.SwitchTo(NewPlayScene(m.sm))
+	}
+	return nil
+}
+
+// Draw implements Scene.
+func (m *MenuScene) Draw(screen *ebiten.Image) {
+	screen.Fill(color.RGBA{R: 10, G: 10, B: 10, A: 255})
+	ebitenutil.DebugPrintAt(screen, "SNAKE", screenW/2-20, screenH/2-60)
+	ebitenutil.DebugPrintAt(screen, "Arrow keys/WASD: choose difficulty. Enter: start.", screenW/2-160, screenH/2-20)
+
+	for i, d := range []Difficulty{DifficultyEasy, DifficultyNormal, DifficultyHard} {
+		marker := "  "
+		if d == m.difficulty {
+			marker = "> "
+		}
+		ebitenutil.DebugPrintAt(screen, marker+d.String(), screenW/2-20, screenH/2+i*16)
+	}
+}
+
+// Layout implements Scene.
+func (m *MenuScene) Layout(outsideWidth, outsideHeight int) (int, int) {
+	return screenW, screenH
+}
+
+// === PlayScene ===
+
+// PlayScene runs the active snake simulation.
+type PlayScene struct {
+	sm *SceneManager
+}
+
+// NewPlayScene creates the gameplay scene. Callers must have already
+// reset the game's simulation state (see MenuScene.Update).
+func NewPlayScene(sm *SceneManager) *PlayScene {
+	return &PlayScene{sm: sm}
+}
+
+// Update implements Scene.
+func (p *PlayScene) Update() error {
+	g := p.sm.game
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyP) || inpututil.IsKeyJustPressed(ebiten.KeyEscape) ||
+		gamepadButtonJustPressed(ebiten.StandardGamepadButtonCenterRight) {
+		p.sm.Push(NewPauseScene(p.sm))
+		return nil
+	}
+
+	g.pollDirection()
+
+	// Control the update rate (snake speed)
+	g.tickCount++
+	if g.tickCount < g.tickSpeed {
+		return nil
+	}
+	g.tickCount = 0
+
+	g.tick()
+
+	if g.gameOver {
+		if g.recording {
+			if path, err := SaveReplay(g.toReplay(), g.startedAt); err != nil {
+				log.Printf("snake: saving replay: %v", err)
+			} else {
+				log.Printf("snake: replay saved to %s", path)
+			}
+		}
+		p.sm.SwitchTo(NewGameOverScene(p.sm))
+	}
+
+	return nil
+}
+
+// Draw implements Scene.
+func (p *PlayScene) Draw(screen *ebiten.Image) {
+	g := p.sm.game
+	screen.Fill(color.RGBA{R: 10, G: 10, B: 10, A: 255})
+
+	drawTile := func(pt Point, img *ebiten.Image) {
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Translate(float64(pt.X*tileSize), float64(pt.Y*tileSize))
+		screen.DrawImage(img, op)
+	}
+
+	if g.tiles != nil {
+		foodCoord := tileFoodFrame0
+		if (g.frame/30)%2 == 1 {
+			foodCoord = tileFoodFrame1
+		}
+		drawTile(g.food, g.tiles.TileAt(foodCoord[0], foodCoord[1]))
+
+		for i, pt := range g.snake {
+			var coord [2]int
+			switch {
+			case i == 0:
+				coord = headTileFor(g.dir)
+			case i == len(g.snake)-1:
+				coord = tailTileFor(g.snake[i-1], pt)
+			default:
+				coord = bodyTileFor(g.snake[i-1], pt, g.snake[i+1])
+			}
+			drawTile(pt, g.tiles.TileAt(coord[0], coord[1]))
+		}
+	} else {
+		drawTile(g.food, g.tileFood)
+		for i, pt := range g.snake {
+			if i == 0 {
+				drawTile(pt, g.tileHead)
+			} else {
+				drawTile(pt, g.tileSnake)
+			}
+		}
+	}
+
+	for _, it := range g.items {
+		drawTile(it.Pos, g.itemTiles[it.Type])
+	}
+
+	// HUD info
+	ebitenutil.DebugPrintAt(screen, fmt.Sprintf("Score: %d  High score: %d", g.score, g.scoreboard.HighScore()), 4, 4)
+	ebitenutil.DebugPrintAt(screen, fmt.Sprintf("Speed (frames/move): %d", g.tickSpeed), 4, 20)
+	ebitenutil.DebugPrintAt(screen, "P/Esc/Start: pause", 4, 36)
+
+	if line := activeEffectsLine(g); line != "" {
+		ebitenutil.DebugPrintAt(screen, line, 4, 52)
+	}
+}
+
+// activeEffectsLine renders each currently active effect with its
+// remaining tick count, e.g. "Ghost(12) Slow-Mo(5)".
+func activeEffectsLine(g *Game) string {
+	line := ""
+	for _, t := range itemTypes {
+		if ticks := g.activeEffects[t]; ticks > 0 {
+			line += fmt.Sprintf("%s(%d) ", t, ticks)
+		}
+	}
+	if g.doubleFoodsLeft > 0 {
+		line += fmt.Sprintf("2x Score(%d foods) ", g.doubleFoodsLeft)
+	}
+	return line
+}
+
+// Layout implements Scene.
+func (p *PlayScene) Layout(outsideWidth, outsideHeight int) (int, int) {
+	return screenW, screenH
+}
+
+// === PauseScene ===
+
+// PauseScene sits on top of a paused PlayScene, freezing the simulation
+// until the player resumes.
+type PauseScene struct {
+	sm *SceneManager
+}
+
+// NewPauseScene creates a pause overlay for the play scene beneath it.
+func NewPauseScene(sm *SceneManager) *PauseScene {
+	return &PauseScene{sm: sm}
+}
+
+// Update implements Scene.
+func (p *PauseScene) Update() error {
+	if inpututil.IsKeyJustPressed(ebiten.KeyP) || inpututil.IsKeyJustPressed(ebiten.KeyEscape) ||
+		gamepadButtonJustPressed(ebiten.StandardGamepadButtonCenterRight) {
+		p.sm.Pop()
+	}
+	return nil
+}
+
+// Draw implements Scene: the frozen play scene beneath, then the overlay.
+func (p *PauseScene) Draw(screen *ebiten.Image) {
+	if len(p.sm.stack) >= 2 {
+		p.sm.stack[len(p.sm.stack)-2].Draw(screen)
+	}
+	ebitenutil.DebugPrintAt(screen, "PAUSED - P/Esc to resume", screenW/2-80, screenH/2)
+}
+
+// Layout implements Scene.
+func (p *PauseScene) Layout(outsideWidth, outsideHeight int) (int, int) {
+	return screenW, screenH
+}
+
+// === GameOverScene ===
+
+// GameOverScene shows the final score and elapsed time, takes the
+// player's initials if they made the top-10, then shows the scoreboard
+// with their run highlighted before offering a restart back to the menu.
+type GameOverScene struct {
+	sm       *SceneManager
+	score    int
+	length   int
+	duration time.Duration
+	mode     string
+
+	initials [3]byte
+	cursor   int
+
+	entered       bool
+	recordedEntry ScoreEntry
+}
+
+// NewGameOverScene snapshots the just-finished run's stats.
+func NewGameOverScene(sm *SceneManager) *GameOverScene {
+	g := sm.game
+	return &GameOverScene{
+		sm:       sm,
+		score:    g.score,
+		length:   len(g.snake),
+		duration: time.Since(g.startedAt),
+		mode:     g.controllerName(),
+		initials: [3]byte{'A', 'A', 'A'},
+	}
+}
+
+// Update implements Scene.
+func (o *GameOverScene) Update() error {
+	if !o.entered {
+		o.updateInitialsEntry()
+		return nil
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeyR) ||
+		gamepadButtonJustPressed(ebiten.StandardGamepadButtonRightBottom) {
+		o.sm.SwitchTo(NewMenuScene(o.sm))
+	}
+	return nil
+}
+
+// updateInitialsEntry handles the three-character initials field: arrow
+// keys move the cursor and cycle the selected letter, Enter confirms and
+// records the run on the scoreboard.
+func (o *GameOverScene) updateInitialsEntry() {
+	if inpututil.IsKeyJustPressed(ebiten.KeyArrowLeft) || gamepadButtonJustPressed(ebiten.StandardGamepadButtonLeftLeft) {
+		if o.cursor > 0 {
+			o.cursor--
+		}
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyArrowRight) || gamepadButtonJustPressed(ebiten.StandardGamepadButtonLeftRight) {
+		if o.cursor < len(o.initials)-1 {
+			o.cursor++
+		}
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyArrowUp) || gamepadButtonJustPressed(ebiten.StandardGamepadButtonLeftTop) {
+		o.initials[o.cursor] = cycleInitial(o.initials[o.cursor], 1)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyArrowDown) || gamepadButtonJustPressed(ebiten.StandardGamepadButtonLeftBottom) {
+		o.initials[o.cursor] = cycleInitial(o.initials[o.cursor], -1)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyEnter) || gamepadButtonJustPressed(ebiten.StandardGamepadButtonRightBottom) {
+		g := o.sm.game
+		o.recordedEntry = ScoreEntry{
+			Initials: string(o.initials[:]),
+			Score:    o.score,
+			Length:   o.length,
+			Duration: o.duration,
+			Mode:     o.mode,
+			When:     time.Now(),
+		}
+		if _, err := g.scoreboard.Add(o.recordedEntry); err != nil {
+			log.Printf("snake: saving scoreboard: %v", err)
+		}
+		o.entered = true
+	}
+}
+
+// cycleInitial steps b ('A'-'Z') by delta, wrapping around.
+func cycleInitial(b byte, delta int) byte {
+	const n = 26
+	idx := (int(b-'A') + delta%n + n) % n
+	return byte('A' + idx)
+}
+
+// Draw implements Scene.
+func (o *GameOverScene) Draw(screen *ebiten.Image) {
+	screen.Fill(color.RGBA{R: 10, G: 10, B: 10, A: 255})
+	ebitenutil.DebugPrintAt(screen, "GAME OVER", screenW/2-40, screenH/2-90)
+	ebitenutil.DebugPrintAt(screen, fmt.Sprintf("Score: %d  Time: %.1fs", o.score, o.duration.Seconds()), screenW/2-70, screenH/2-70)
+
+	if !o.entered {
+		o.drawInitialsEntry(screen)
+		return
+	}
+
+	g := o.sm.game
+	ebitenutil.DebugPrintAt(screen, "TOP SCORES", screenW/2-40, screenH/2-40)
+	for i, e := range g.scoreboard.Entries {
+		line := fmt.Sprintf("%2d. %-3s %5d  %s", i+1, e.Initials, e.Score, e.Mode)
+		if e == o.recordedEntry {
+			line = "> " + line
+		} else {
+			line = "  " + line
+		}
+		ebitenutil.DebugPrintAt(screen, line, screenW/2-120, screenH/2-20+i*16)
+	}
+	ebitenutil.DebugPrintAt(screen, "[ Play again (Enter) ]", screenW/2-70, screenH/2+150)
+}
+
+// drawInitialsEntry renders the three-character initials field with the
+// selected letter boxed.
+func (o *GameOverScene) drawInitialsEntry(screen *ebiten.Image) {
+	ebitenutil.DebugPrintAt(screen, "Enter your initials:", screenW/2-60, screenH/2-40)
+
+	line := ""
+	for i, c := range o.initials {
+		if i == o.cursor {
+			line += "[" + string(c) + "]"
+		} else {
+			line += " " + string(c) + " "
+		}
+	}
+	ebitenutil.DebugPrintAt(screen, line, screenW/2-20, screenH/2-20)
+	ebitenutil.DebugPrintAt(screen, "Left/Right: select. Up/Down: change. Enter: confirm.", screenW/2-170, screenH/2)
+}
+
+// Layout implements Scene.
+func (o *GameOverScene) Layout(outsideWidth, outsideHeight int) (int, int) {
+	return screenW, screenH
+}
+
+// === ReplayScene ===
+
+// ReplayScene drives the same simulation as PlayScene but from a
+// ReplayController, with pause/step/double-speed controls layered on
+// top for reviewing a recorded run.
+type ReplayScene struct {
+	sm      *SceneManager
+	paused  bool
+	speedX2 bool
+}
+
+// NewReplayScene creates the playback scene. The game's controller must
+// already be a *ReplayController seeded from the same Replay (see main).
+func NewReplayScene(sm *SceneManager) *ReplayScene {
+	return &ReplayScene{sm: sm}
+}
+
+// Update implements Scene.
+func (r *ReplayScene) Update() error {
+	g := r.sm.game
+
+	if inpututil.IsKeyJustPressed(ebiten.KeySpace) {
+		r.paused = !r.paused
+	}
+	if inpututil.IsKeyJustPressed(ebiten.Key2) {
+		r.speedX2 = !r.speedX2
+	}
+
+	g.pollDirection()
+
+	if r.paused {
+		if inpututil.IsKeyJustPressed(ebiten.KeyPeriod) || inpututil.IsKeyJustPressed(ebiten.KeyArrowRight) {
+			r.advance()
+		}
+		return nil
+	}
+
+	steps := 1
+	if r.speedX2 {
+		steps = 2
+	}
+	for s := 0; s < steps && !g.gameOver; s++ {
+		r.advance()
+	}
+	return nil
+}
+
+// advance gates and runs a single simulation tick, switching to the
+// game-over screen if the replayed run ends.
+func (r *ReplayScene) advance() {
+	g := r.sm.game
+	g.tickCount++
+	if g.tickCount < g.tickSpeed {
+		return
+	}
+	g.tickCount = 0
+	g.tick()
+	if g.gameOver {
+		r.sm.SwitchTo(NewGameOverScene(r.sm))
+	}
+}
+
+// Draw implements Scene: the normal play rendering, plus a status line.
+func (r *ReplayScene) Draw(screen *ebiten.Image) {
+	(&PlayScene{sm: r.sm}).Draw(screen)
+
+	status := "PLAYING"
+	switch {
+	case r.paused:
+		status = "PAUSED (./-> to step)"
+	case r.speedX2:
+		status = "PLAYING 2x"
+	}
+	ebitenutil.DebugPrintAt(screen, "REPLAY: "+status+" (Space: pause, 2: 2x speed)", 4, 68)
+}
+
+// Layout implements Scene.
+func (r *ReplayScene) Layout(outsideWidth, outsideHeight int) (int, int) {
+	return screenW, screenH
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// scoreboardSize is how many runs the scoreboard keeps.
+const scoreboardSize = 10
+
+// ScoreEntry is one completed run recorded on the scoreboard.
+type ScoreEntry struct {
+	Initials string        `json:"initials"`
+	Score    int           `json:"score"`
+	Length   int           `json:"length"`
+	Duration time.Duration `json:"duration"`
+	Mode     string        `json:"mode"`
+	When     time.Time     `json:"when"`
+}
+
+// Scoreboard keeps the top scoreboardSize runs, persisted as JSON under
+// the user's config directory.
+type Scoreboard struct {
+	path    string
+	Entries []ScoreEntry `json:"entries"`
+}
+
+// scoreboardPath returns the on-disk location of the scores file.
+func scoreboardPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "snake", "scores.json"), nil
+}
+
+// LoadScoreboard reads the persisted scoreboard, returning an empty one
+// if it doesn't exist yet or the config directory can't be determined.
+func LoadScoreboard() *Scoreboard {
+	path, err := scoreboardPath()
+	if err != nil {
+		return &Scoreboard{}
+	}
+	sb := &Scoreboard{path: path}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return sb
+	}
+	if err := json.Unmarshal(data, sb); err != nil {
+		return &Scoreboard{path: path}
+	}
+	return sb
+}
+
+// Reset clears the scoreboard in memory and removes the file on disk.
+func (sb *Scoreboard) Reset() error {
+	sb.Entries = nil
+	if sb.path == "" {
+		return nil
+	}
+	if err := os.Remove(sb.path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// HighScore returns the best recorded score, or 0 if the board is empty.
+func (sb *Scoreboard) HighScore() int {
+	if len(sb.Entries) == 0 {
+		return 0
+	}
+	return sb.Entries[0].Score
+}
+
+// Add inserts entry in ranked order, trims the board to scoreboardSize,
+// persists it to disk, and reports whether entry made the cut.
+func (sb *Scoreboard) Add(entry ScoreEntry) (bool, error) {
+	sb.Entries = append(sb.Entries, entry)
+	sort.SliceStable(sb.Entries, func(i, j int) bool {
+		return sb.Entries[i].Score > sb.Entries[j].Score
+	})
+
+	if len(sb.Entries) > scoreboardSize {
+		sb.Entries = sb.Entries[:scoreboardSize]
+	}
+
+	madeCut := false
+	for _, e := range sb.Entries {
+		if e == entry {
+			madeCut = true
+			break
+		}
+	}
+
+	return madeCut, sb.save()
+}
+
+// save atomically rewrites the scoreboard file: write to a temp file in
+// the same directory, then rename over the original.
+func (sb *Scoreboard) save() error {
+	if sb.path == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(sb.path), 0o755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(sb, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := sb.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, sb.path)
+}
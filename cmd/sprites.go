@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bytes"
+	_ "embed"
+	"image"
+	"image/color"
+	"image/png"
+	"log"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+//go:embed assets/tiles.png
+var tilesheetPNG []byte
+
+// tilesCols/tilesRows describe the grid of the embedded tilesheet.
+const (
+	tilesCols = 6
+	tilesRows = 3
+)
+
+// Tilesheet holds the decoded sprite sheet and hands out sub-images for
+// individual tiles, following the FishTileAt/FishTileXY pattern used for
+// the fishfightback assets.
+type Tilesheet struct {
+	sheet *ebiten.Image
+}
+
+// loadTilesheet decodes the embedded PNG into an *ebiten.Image. It
+// returns an error rather than panicking so callers can fall back to
+// flat-color tiles if decoding ever fails.
+func loadTilesheet() (*Tilesheet, error) {
+	img, err := png.Decode(bytes.NewReader(tilesheetPNG))
+	if err != nil {
+		return nil, err
+	}
+	return &Tilesheet{sheet: ebiten.NewImageFromImage(img)}, nil
+}
+
+// TileAt returns the sub-image for the tile at the given column/row in
+// the sheet.
+func (t *Tilesheet) TileAt(col, row int) *ebiten.Image {
+	x, y := col*tileSize, row*tileSize
+	rect := image.Rect(x, y, x+tileSize, y+tileSize)
+	return t.sheet.SubImage(rect).(*ebiten.Image)
+}
+
+// Named tile coordinates within the sheet (see cmd/assets/tiles.png).
+var (
+	tileHeadUp    = [2]int{0, 0}
+	tileHeadDown  = [2]int{1, 0}
+	tileHeadLeft  = [2]int{2, 0}
+	tileHeadRight = [2]int{3, 0}
+
+	tileTailUp    = [2]int{0, 1}
+	tileTailDown  = [2]int{1, 1}
+	tileTailLeft  = [2]int{2, 1}
+	tileTailRight = [2]int{3, 1}
+
+	tileBodyHorizontal = [2]int{4, 0}
+	tileBodyVertical   = [2]int{4, 1}
+	tileBodyCorner     = [2]int{0, 2} // all four corners share one sprite
+
+	tileFoodFrame0 = [2]int{2, 2}
+	tileFoodFrame1 = [2]int{3, 2}
+)
+
+// bodyTileFor picks the body sprite for the segment at index i (1-based
+// from the head) given its neighbours, based on whether the snake turns
+// between them.
+func bodyTileFor(prev, cur, next Point) [2]int {
+	inDir := Point{cur.X - prev.X, cur.Y - prev.Y}
+	outDir := Point{next.X - cur.X, next.Y - cur.Y}
+
+	if inDir == outDir {
+		if inDir.X != 0 {
+			return tileBodyHorizontal
+		}
+		return tileBodyVertical
+	}
+	return tileBodyCorner
+}
+
+// headTileFor picks the directional head sprite for the current heading.
+func headTileFor(dir Point) [2]int {
+	switch {
+	case dir.Y == -1:
+		return tileHeadUp
+	case dir.Y == 1:
+		return tileHeadDown
+	case dir.X == -1:
+		return tileHeadLeft
+	default:
+		return tileHeadRight
+	}
+}
+
+// tailTileFor picks the directional tail sprite from the direction the
+// segment before the tail is heading into it.
+func tailTileFor(beforeTail, tail Point) [2]int {
+	dir := Point{tail.X - beforeTail.X, tail.Y - beforeTail.Y}
+	switch {
+	case dir.Y == -1:
+		return tileTailUp
+	case dir.Y == 1:
+		return tileTailDown
+	case dir.X == -1:
+		return tileTailLeft
+	default:
+		return tileTailRight
+	}
+}
+
+// tilesheetOrFallback loads the embedded tilesheet, logging and falling
+// back to nil (flat-color rendering) if it can't be decoded.
+func tilesheetOrFallback() *Tilesheet {
+	ts, err := loadTilesheet()
+	if err != nil {
+		log.Printf("snake: loading tilesheet, falling back to flat tiles: %v", err)
+		return nil
+	}
+	return ts
+}
+
+// fallbackTile is a flat-colored tile used when the tilesheet failed to
+// load.
+func fallbackTile(c color.RGBA) *ebiten.Image {
+	img := ebiten.NewImage(tileSize, tileSize)
+	img.Fill(c)
+	return img
+}